@@ -11,10 +11,16 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 )
 
-// SetClusterConfigDefaults will set defaults for a given cluster
-func SetClusterConfigDefaults(cfg *ClusterConfig) {
+// SetClusterConfigDefaults sets defaults for a given cluster and returns an InstanceTypeResolver
+// prefetched with every distinct instance type referenced by the cluster's nodegroups, so that the
+// caller's own per-nodegroup defaulting pass (SetNodeGroupDefaults, which takes the resolver) reads
+// from its cache instead of issuing DescribeInstanceTypes calls per nodegroup. This function does
+// NOT default the nodegroups itself, to avoid double-defaulting in callers that already loop over
+// cfg.NodeGroups/cfg.ManagedNodeGroups after calling this.
+func SetClusterConfigDefaults(cfg *ClusterConfig) (*InstanceTypeResolver, error) {
 	if cfg.IAM == nil {
 		cfg.IAM = &ClusterIAM{}
 	}
@@ -35,10 +41,40 @@ func SetClusterConfigDefaults(cfg *ClusterConfig) {
 			cfg.CloudWatch.ClusterLogging.EnableTypes = SupportedCloudWatchClusterLogTypes()
 		}
 	}
+
+	resolver, err := NewInstanceTypeResolver(cfg.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolver.Prefetch(collectInstanceTypes(cfg)); err != nil {
+		return nil, err
+	}
+
+	return resolver, nil
+}
+
+// collectInstanceTypes gathers every distinct instance type referenced by the cluster's nodegroups,
+// including the InstanceTypes of mixed-instance NodeGroups, so they can be resolved in one batch.
+func collectInstanceTypes(cfg *ClusterConfig) []string {
+	var instanceTypes []string
+	for _, ng := range cfg.NodeGroups {
+		if ng.InstanceType != "" && ng.InstanceType != "mixed" {
+			instanceTypes = append(instanceTypes, ng.InstanceType)
+		}
+		if ng.InstancesDistribution != nil {
+			instanceTypes = append(instanceTypes, ng.InstancesDistribution.InstanceTypes...)
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if ng.InstanceType != "" {
+			instanceTypes = append(instanceTypes, ng.InstanceType)
+		}
+	}
+	return instanceTypes
 }
 
 // SetNodeGroupDefaults will set defaults for a given nodegroup
-func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
+func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta, resolver *InstanceTypeResolver) error {
 	if ng.InstanceType == "" {
 		if HasMixedInstances(ng) {
 			ng.InstanceType = "mixed"
@@ -89,7 +125,7 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 		ng.KubeletExtraConfig = &InlineDocument{}
 	}
 
-	SetKubeletExtraConfigDefaults(ng, meta)
+	return SetKubeletExtraConfigDefaults(ng, resolver)
 }
 
 // SetManagedNodeGroupDefaults sets default values for a ManagedNodeGroup
@@ -184,8 +220,41 @@ func setDefaultNodeLabels(labels map[string]string, clusterName, nodeGroupName s
 	labels[NodeGroupNameLabel] = nodeGroupName
 }
 
-type getRscDefaultFunc func(string, *ClusterMeta) (string, error)
-type setRscDefaultFunc func(*NodeGroup, string, *ClusterMeta, getRscDefaultFunc) error
+type getRscDefaultFunc func(string, *InstanceTypeResolver, ReservationMode) (string, error)
+type setRscDefaultFunc func(*NodeGroup, string, *InstanceTypeResolver, ReservationMode, getRscDefaultFunc) error
+
+// ReservationMode controls how kubeReserved/systemReserved values are derived for instance types
+// that aren't an exact match in the hard-coded reservation tables.
+type ReservationMode string
+
+const (
+	// ReservationModeInterpolate linearly interpolates between the two nearest tabulated entries,
+	// capped at the largest tabulated value. This is the default.
+	ReservationModeInterpolate ReservationMode = "Interpolate"
+	// ReservationModeExact requires an exact match in the reservation table and errors otherwise,
+	// matching eksctl's original strict behavior.
+	ReservationModeExact ReservationMode = "Exact"
+	// ReservationModeSkip leaves the reservation key unset so kubelet falls back to its own
+	// defaults, mirroring the ekstrap approach of emitting nothing rather than a fabricated value.
+	ReservationModeSkip ReservationMode = "Skip"
+)
+
+const reservationModeKey = "reservationMode"
+
+// popReservationMode reads and removes reservationModeKey from kec. It's a control knob for this
+// defaulting pass, not a real kubelet option, so it must never survive into the serialized
+// KubeletExtraConfig that ends up on the node.
+func popReservationMode(kec InlineDocument) ReservationMode {
+	v, ok := kec[reservationModeKey].(string)
+	delete(kec, reservationModeKey)
+	if ok {
+		switch ReservationMode(v) {
+		case ReservationModeExact, ReservationModeSkip, ReservationModeInterpolate:
+			return ReservationMode(v)
+		}
+	}
+	return ReservationModeInterpolate
+}
 
 type rscParamSet struct {
 	setFun  setRscDefaultFunc `json: "setFun,omitEmpty"`
@@ -199,93 +268,494 @@ var rscParams = []rscParamSet{
 	{setFun: setEphemeralStorageDefaults, getFun: getEphemeralStorageReservations, rscType: "ephemeral-storage"},
 }
 
-// SetKubeletExtraConfigDefaults adds Kubelet CPU, Mem, and Storage Reservation default values for a nodegroup
-func SetKubeletExtraConfigDefaults(ng *NodeGroup, meta *ClusterMeta) error {
+// systemRscParams mirrors rscParams but populates systemReserved instead of kubeReserved, covering
+// the portion of instance capacity set aside for host-level system daemons (sshd, udev, journald, etc.)
+// rather than Kubernetes' own components.
+var systemRscParams = []rscParamSet{
+	{setFun: setSystemCpuReservationsDefaults, getFun: getSystemCpuReservations, rscType: "cpu"},
+	{setFun: setSystemMemoryReservationsDefaults, getFun: getSystemMemReservations, rscType: "memory"},
+}
+
+// SetKubeletExtraConfigDefaults adds Kubelet CPU, Mem, and Storage Reservation default values for a
+// nodegroup, following the upstream Node Allocatable model: Allocatable = Capacity - kubeReserved -
+// systemReserved - evictionHard. See: https://kubernetes.io/docs/tasks/administer-cluster/reserve-compute-resources/
+func SetKubeletExtraConfigDefaults(ng *NodeGroup, resolver *InstanceTypeResolver) error {
+	kec := (*ng).KubeletExtraConfig
+	if kec == nil {
+		kec = &InlineDocument{}
+	}
+	// Consumed once up front (and stripped from kec) so every rscParams/systemRscParams pass agrees
+	// on the same mode instead of each re-deriving it from a key that's since been removed.
+	mode := popReservationMode(*kec)
+	ng.KubeletExtraConfig = kec
+
 	for _, pSet := range rscParams {
-		err := pSet.setFun(ng, pSet.rscType, meta, pSet.getFun)
-		if err != nil {
+		if err := pSet.setFun(ng, pSet.rscType, resolver, mode, pSet.getFun); err != nil {
 			return err
 		}
 	}
-	return nil
+	for _, pSet := range systemRscParams {
+		if err := pSet.setFun(ng, pSet.rscType, resolver, mode, pSet.getFun); err != nil {
+			return err
+		}
+	}
+	setEvictionHardDefaults(ng)
+	setEnforceNodeAllocatableDefaults(ng)
+
+	if err := setMemoryManagerDefaults(ng, resolver); err != nil {
+		return err
+	}
+
+	return validateNodeAllocatable(ng, resolver)
+}
+
+func setCpuReservationsDefaults(ng *NodeGroup, rscType string, resolver *InstanceTypeResolver, mode ReservationMode, gfn getRscDefaultFunc) error {
+	return setReservationDefault(ng, rscType, resolver, mode, gfn)
+}
+
+func setMemoryResevationDefaults(ng *NodeGroup, rscType string, resolver *InstanceTypeResolver, mode ReservationMode, gfn getRscDefaultFunc) error {
+	return setReservationDefault(ng, rscType, resolver, mode, gfn)
+}
+
+func setEphemeralStorageDefaults(ng *NodeGroup, rscType string, resolver *InstanceTypeResolver, mode ReservationMode, gfn getRscDefaultFunc) error {
+	return setReservationDefault(ng, rscType, resolver, mode, gfn)
 }
 
-func setCpuReservationsDefaults(ng *NodeGroup, rscType string, meta *ClusterMeta, gfn getRscDefaultFunc) error {
-	return setReservationDefault(ng, rscType, meta, gfn)
+func setSystemCpuReservationsDefaults(ng *NodeGroup, rscType string, resolver *InstanceTypeResolver, mode ReservationMode, gfn getRscDefaultFunc) error {
+	return setReservationDefaultForKey(ng, rscType, resolver, mode, gfn, "systemReserved")
 }
 
-func setMemoryResevationDefaults(ng *NodeGroup, rscType string, meta *ClusterMeta, gfn getRscDefaultFunc) error {
-	return setReservationDefault(ng, rscType, meta, gfn)
+func setSystemMemoryReservationsDefaults(ng *NodeGroup, rscType string, resolver *InstanceTypeResolver, mode ReservationMode, gfn getRscDefaultFunc) error {
+	return setReservationDefaultForKey(ng, rscType, resolver, mode, gfn, "systemReserved")
 }
 
-func setEphemeralStorageDefaults(ng *NodeGroup, rscType string, meta *ClusterMeta, gfn getRscDefaultFunc) error {
-	return setReservationDefault(ng, rscType, meta, gfn)
+func setReservationDefault(ng *NodeGroup, resType string, resolver *InstanceTypeResolver, mode ReservationMode, fn getRscDefaultFunc) error {
+	return setReservationDefaultForKey(ng, resType, resolver, mode, fn, "kubeReserved")
 }
 
-func setReservationDefault(ng *NodeGroup, resType string, meta *ClusterMeta, fn getRscDefaultFunc) error {
+// setReservationDefaultForKey computes a reserved resource value and merges it into the given
+// KubeletExtraConfig map (e.g. "kubeReserved" or "systemReserved"), skipping resource types the
+// user has already set explicitly. mode is resolved once by the caller (SetKubeletExtraConfigDefaults)
+// rather than re-read here, since reservationModeKey is stripped from kec as soon as it's consumed.
+func setReservationDefaultForKey(ng *NodeGroup, resType string, resolver *InstanceTypeResolver, mode ReservationMode, fn getRscDefaultFunc, docKey string) error {
 	kec := (*ng).KubeletExtraConfig
 	if kec == nil {
 		kec = &InlineDocument{}
 	}
-	rsrcRes, err := fn((*ng).InstanceType, meta)
+	if mode == ReservationModeSkip {
+		ng.KubeletExtraConfig = kec
+		return nil
+	}
+
+	rsrcRes, err := fn((*ng).InstanceType, resolver, mode)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w (reservationMode: %s)", err, mode)
 	}
-	kubeReserved := getKubeReserved(*kec)
+	reserved := getReservedMap(*kec, docKey)
 	// only set kubelet reservations for resource types that aren't already set in config
-	if _, ok := kubeReserved[resType]; !ok {
-		kubeReserved[resType] = rsrcRes
+	if _, ok := reserved[resType]; !ok {
+		reserved[resType] = rsrcRes
 	}
-	(*kec)["kubeReserved"] = kubeReserved
+	(*kec)[docKey] = reserved
 	ng.KubeletExtraConfig = kec
 	return nil
 }
 
-func getKubeReserved(kec InlineDocument) map[string]interface{} {
-	kubeReserved, ok := kec["kubeReserved"].(map[string]interface{})
+func getReservedMap(kec InlineDocument, docKey string) map[string]interface{} {
+	reserved, ok := kec[docKey].(map[string]interface{})
 	if !ok {
-		kubeReserved = make(map[string]interface{})
+		reserved = make(map[string]interface{})
 	}
-	return kubeReserved
+	return reserved
 }
 
-type cpuEntry struct {
-	cores int64
-	res   string
+// systemCpuReservationTable approximates the CPU reserved for host-level system daemons, kept
+// modest relative to cpuReservationTable since systemReserved only needs to cover non-Kubernetes
+// processes. Sorted by cores ascending.
+var systemCpuReservationTable = []cpuReservationEntry{
+	{cores: 1, millicore: 50},
+	{cores: 2, millicore: 100},
+	{cores: 4, millicore: 100},
+	{cores: 8, millicore: 100},
+	{cores: 16, millicore: 120},
+	{cores: 32, millicore: 160},
+	{cores: 48, millicore: 200},
+	{cores: 64, millicore: 240},
+	{cores: 96, millicore: 320},
 }
 
-// See: https://docs.microsoft.com/en-us/azure/aks/concepts-clusters-workloads
-var cpuAllocations map[int64]string = map[int64]string{
-	1:  "60m",
-	2:  "100m",  //+40
-	4:  "140m",  //+40
-	8:  "180m",  //+40
-	16: "260m",  //+80
-	32: "420m",  //+160
-	48: "580m",  //+160
-	64: "740m",  //+320
-	96: "1040m", //+320
-}
-
-func getCpuReservations(it string, meta *ClusterMeta) (string, error) {
-	cores, err := getInstanceTypeCores(it, meta)
+func getSystemCpuReservations(it string, resolver *InstanceTypeResolver, mode ReservationMode) (string, error) {
+	cores, err := getInstanceTypeCores(it, resolver)
+	if err != nil {
+		return "", err
+	}
+	if mode == ReservationModeExact {
+		for _, e := range systemCpuReservationTable {
+			if e.cores == cores {
+				return fmt.Sprintf("%dm", e.millicore), nil
+			}
+		}
+		return "", fmt.Errorf("no exact system CPU reservation for instance type %s (%d vCPUs)", it, cores)
+	}
+	return fmt.Sprintf("%dm", interpolateCpuReservation(systemCpuReservationTable, cores)), nil
+}
+
+// systemMemBaselineMi is the minimum memory set aside for system daemons, scaled up modestly for
+// instances with a large amount of total memory.
+const systemMemBaselineMi = 100.0
+
+func getSystemMemReservations(it string, resolver *InstanceTypeResolver, mode ReservationMode) (string, error) {
+	instMem, err := getInstanceTypeMem(it, resolver)
 	if err != nil {
 		return "", err
 	}
+	reserved := systemMemBaselineMi
+	switch {
+	case instMem > 128:
+		reserved = systemMemBaselineMi * 2
+	case instMem > 32:
+		reserved = systemMemBaselineMi * 1.5
+	}
+	return formatMem(reserved), nil
+}
+
+// evictionHardDefaults are the kubelet eviction thresholds applied when the user hasn't configured
+// their own, matching commonly recommended baselines for on-demand worker nodes.
+func evictionHardDefaults() map[string]interface{} {
+	return map[string]interface{}{
+		"memory.available":  "100Mi",
+		"nodefs.available":  "10%",
+		"nodefs.inodesFree": "5%",
+		"imagefs.available": "15%",
+	}
+}
+
+func setEvictionHardDefaults(ng *NodeGroup) {
+	kec := (*ng).KubeletExtraConfig
+	if kec == nil {
+		kec = &InlineDocument{}
+	}
+	if _, ok := (*kec)["evictionHard"]; !ok {
+		(*kec)["evictionHard"] = evictionHardDefaults()
+	}
+	ng.KubeletExtraConfig = kec
+}
+
+const (
+	defaultKubeReservedCgroup   = "/kube-reserved"
+	defaultSystemReservedCgroup = "/system-reserved"
+)
+
+// setEnforceNodeAllocatableDefaults defaults enforceNodeAllocatable to ["pods"], and when the user
+// opts into enforcing kube-reserved/system-reserved as well, fills in the matching cgroup paths
+// kubelet requires to do so.
+func setEnforceNodeAllocatableDefaults(ng *NodeGroup) {
+	kec := (*ng).KubeletExtraConfig
+	if kec == nil {
+		kec = &InlineDocument{}
+	}
+
+	var enforced []string
+	switch v := (*kec)["enforceNodeAllocatable"].(type) {
+	case []string:
+		enforced = v
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				enforced = append(enforced, s)
+			}
+		}
+	}
+	if len(enforced) == 0 {
+		enforced = []string{"pods"}
+	}
+	(*kec)["enforceNodeAllocatable"] = enforced
+
+	for _, e := range enforced {
+		switch e {
+		case "kube-reserved":
+			if _, ok := (*kec)["kubeReservedCgroup"]; !ok {
+				(*kec)["kubeReservedCgroup"] = defaultKubeReservedCgroup
+			}
+		case "system-reserved":
+			if _, ok := (*kec)["systemReservedCgroup"]; !ok {
+				(*kec)["systemReservedCgroup"] = defaultSystemReservedCgroup
+			}
+		}
+	}
+	ng.KubeletExtraConfig = kec
+}
+
+const memoryManagerPolicyStatic = "Static"
+
+// burstableInstanceFamilyPrefixes are the burstable T-series families (t2, t3, t3a, t4g) that
+// setMemoryManagerDefaults rejects for static policies, since those rely on guaranteed, pinned
+// cores that burstable instances don't provide.
+var burstableInstanceFamilyPrefixes = []string{"t2", "t3", "t3a", "t4g"}
+
+func isBurstableInstanceType(it string) bool {
+	family := strings.SplitN(it, ".", 2)[0]
+	for _, prefix := range burstableInstanceFamilyPrefixes {
+		if family == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// numaNodesByInstanceSuffix is a fallback table of NUMA node counts for bare-metal and very large
+// instance families, for cases where DescribeInstanceTypes doesn't surface topology directly.
+var numaNodesByInstanceSuffix = map[string]int{
+	"metal":    2,
+	"24xlarge": 2,
+	"32xlarge": 4,
+	"48xlarge": 8,
+}
+
+// getNumaNodeCount determines the NUMA node count for an instance type. DescribeInstanceTypes
+// doesn't expose NUMA topology directly, so known bare-metal/very-large families are matched
+// against numaNodesByInstanceSuffix first; anything else falls back to an estimate derived from
+// the instance's vCPU count (queried via resolver), since EC2's larger, multi-socket shapes track
+// vCPU count far more closely than instance-size suffix alone (e.g. c5.18xlarge).
+func getNumaNodeCount(it string, resolver *InstanceTypeResolver) (int, error) {
+	for suffix, n := range numaNodesByInstanceSuffix {
+		if strings.HasSuffix(it, suffix) {
+			return n, nil
+		}
+	}
+
+	cores, err := getInstanceTypeCores(it, resolver)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case cores >= 96:
+		return 4, nil
+	case cores >= 64:
+		return 2, nil
+	default:
+		return 1, nil
+	}
+}
+
+// setMemoryManagerDefaults is opt-in: it only acts when the user has requested
+// KubeletExtraConfig.MemoryManager.Policy = "Static", in which case it computes one reservedMemory
+// entry per NUMA node (splitting the systemReserved+kubeReserved memory evenly across them) so that
+// Guaranteed pods get NUMA-local memory pinning on multi-socket instances.
+// See: https://kubernetes.io/docs/tasks/administer-cluster/memory-manager/
+func setMemoryManagerDefaults(ng *NodeGroup, resolver *InstanceTypeResolver) error {
+	kec := (*ng).KubeletExtraConfig
+	if kec == nil {
+		return nil
+	}
+	memoryManager, ok := (*kec)["memoryManager"].(map[string]interface{})
+	// memoryManager is a control knob for this defaulting pass, not a real kubelet field, so it must
+	// never survive into the serialized KubeletExtraConfig regardless of whether it activates below.
+	delete(*kec, "memoryManager")
+	if !ok {
+		return nil
+	}
+	policy, _ := memoryManager["policy"].(string)
+	if policy != memoryManagerPolicyStatic {
+		return nil
+	}
+
+	it := (*ng).InstanceType
+	cores, err := getInstanceTypeCores(it, resolver)
+	if err != nil {
+		return err
+	}
+	if cores <= 1 || isBurstableInstanceType(it) {
+		return fmt.Errorf("memoryManager.policy %q requires a non-burstable instance type with more than 1 vCPU, got %q", memoryManagerPolicyStatic, it)
+	}
+
+	numaNodes, err := getNumaNodeCount(it, resolver)
+	if err != nil {
+		return err
+	}
+
+	// memoryManager's NUMA split needs a concrete kubeReserved/systemReserved memory value to divide
+	// up; reservationMode: Skip deliberately leaves both unset, which is incompatible with it.
+	kubeReservedMemory, ok := getReservedMap(*kec, "kubeReserved")["memory"].(string)
+	if !ok {
+		return fmt.Errorf("memoryManager.policy %q requires kubeReserved.memory to be set, but reservationMode %q leaves it unset for instance type %s", memoryManagerPolicyStatic, ReservationModeSkip, it)
+	}
+	kubeReservedMi, err := parseMiValue(kubeReservedMemory)
+	if err != nil {
+		return fmt.Errorf("invalid kubeReserved memory for instance type %s: %w", it, err)
+	}
+	systemReservedMemory, ok := getReservedMap(*kec, "systemReserved")["memory"].(string)
+	if !ok {
+		return fmt.Errorf("memoryManager.policy %q requires systemReserved.memory to be set, but reservationMode %q leaves it unset for instance type %s", memoryManagerPolicyStatic, ReservationModeSkip, it)
+	}
+	systemReservedMi, err := parseMiValue(systemReservedMemory)
+	if err != nil {
+		return fmt.Errorf("invalid systemReserved memory for instance type %s: %w", it, err)
+	}
+
+	perNodeMi := (kubeReservedMi + systemReservedMi) / float64(numaNodes)
+	reservedMemory := make([]map[string]interface{}, numaNodes)
+	for i := 0; i < numaNodes; i++ {
+		reservedMemory[i] = map[string]interface{}{
+			"numaNode": i,
+			"limits": map[string]interface{}{
+				"memory": formatMem(perNodeMi),
+			},
+		}
+	}
+
+	(*kec)["memoryManagerPolicy"] = memoryManagerPolicyStatic
+	(*kec)["reservedMemory"] = reservedMemory
+
+	if cpuPolicy, ok := memoryManager["cpuManagerPolicy"].(string); ok && cpuPolicy == "static" {
+		(*kec)["cpuManagerPolicy"] = "static"
+	}
+	if topologyPolicy, ok := memoryManager["topologyManagerPolicy"].(string); ok && topologyPolicy == "single-numa-node" {
+		(*kec)["topologyManagerPolicy"] = "single-numa-node"
+	}
+
+	ng.KubeletExtraConfig = kec
+	return nil
+}
+
+// parseMilliValue parses a Kubernetes CPU quantity such as "100m" or "1" into millicores.
+func parseMilliValue(v string) (int64, error) {
+	if strings.HasSuffix(v, "m") {
+		return strconv.ParseInt(strings.TrimSuffix(v, "m"), 10, 64)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f * 1000), nil
+}
+
+// parseMiValue parses a Kubernetes memory quantity such as "256.0Mi" into mebibytes. Both
+// kubeReserved.memory and systemReserved.memory are denominated in true MiB, so this is the only
+// memory parser needed anywhere reserved/capacity values are compared.
+func parseMiValue(v string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(v, "Mi"), 64)
+}
+
+// validateNodeAllocatable checks that kubeReserved, systemReserved, and the evictionHard memory
+// threshold don't together reach or exceed the instance type's CPU/memory capacity, since in that
+// case kubelet would be left with no Allocatable resources for pods at all.
+func validateNodeAllocatable(ng *NodeGroup, resolver *InstanceTypeResolver) error {
+	kec := (*ng).KubeletExtraConfig
+	if kec == nil {
+		return nil
+	}
+
+	cores, err := getInstanceTypeCores((*ng).InstanceType, resolver)
+	if err != nil {
+		return err
+	}
+	instMemMi, err := getInstanceTypeMemMi((*ng).InstanceType, resolver)
+	if err != nil {
+		return err
+	}
+
+	cpuCapacity := cores * 1000
+	memCapacity := instMemMi
+	var cpuCommitted int64
+	var memCommitted float64
+
+	for _, docKey := range []string{"kubeReserved", "systemReserved"} {
+		reserved := getReservedMap(*kec, docKey)
+		if v, ok := reserved["cpu"].(string); ok {
+			m, err := parseMilliValue(v)
+			if err != nil {
+				return fmt.Errorf("invalid cpu value %q in %s for instance type %s: %w", v, docKey, (*ng).InstanceType, err)
+			}
+			cpuCommitted += m
+		}
+		if v, ok := reserved["memory"].(string); ok {
+			m, err := parseMiValue(v)
+			if err != nil {
+				return fmt.Errorf("invalid memory value %q in %s for instance type %s: %w", v, docKey, (*ng).InstanceType, err)
+			}
+			memCommitted += m
+		}
+	}
+	if evictionHard, ok := (*kec)["evictionHard"].(map[string]interface{}); ok {
+		if v, ok := evictionHard["memory.available"].(string); ok {
+			m, err := parseMiValue(v)
+			if err != nil {
+				return fmt.Errorf("invalid memory.available value %q in evictionHard for instance type %s: %w", v, (*ng).InstanceType, err)
+			}
+			memCommitted += m
+		}
+	}
 
-	reservedCores := "0"
-	ok := false
-	if reservedCores, ok = cpuAllocations[cores]; !ok {
-		err = fmt.Errorf("Could not find suggested core reservation for instance type: %s\n", it)
+	if cpuCommitted >= cpuCapacity {
+		return fmt.Errorf("cpu reservations (%dm) for instance type %s would leave no allocatable cpu out of %dm capacity", cpuCommitted, (*ng).InstanceType, cpuCapacity)
+	}
+	if memCommitted >= memCapacity {
+		return fmt.Errorf("memory reservations (%.0fMi) for instance type %s would leave no allocatable memory out of %.0fMi capacity", memCommitted, (*ng).InstanceType, memCapacity)
 	}
+	return nil
+}
+
+type cpuReservationEntry struct {
+	cores     int64
+	millicore int64
+}
+
+// cpuReservationTable is sorted by cores ascending.
+// See: https://docs.microsoft.com/en-us/azure/aks/concepts-clusters-workloads
+var cpuReservationTable = []cpuReservationEntry{
+	{cores: 1, millicore: 60},
+	{cores: 2, millicore: 100},   //+40
+	{cores: 4, millicore: 140},   //+40
+	{cores: 8, millicore: 180},   //+40
+	{cores: 16, millicore: 260},  //+80
+	{cores: 32, millicore: 420},  //+160
+	{cores: 48, millicore: 580},  //+160
+	{cores: 64, millicore: 740},  //+160
+	{cores: 96, millicore: 1040}, //+300
+}
+
+func getCpuReservations(it string, resolver *InstanceTypeResolver, mode ReservationMode) (string, error) {
+	cores, err := getInstanceTypeCores(it, resolver)
 	if err != nil {
 		return "", err
 	}
-	return reservedCores, nil
+
+	if mode == ReservationModeExact {
+		for _, e := range cpuReservationTable {
+			if e.cores == cores {
+				return fmt.Sprintf("%dm", e.millicore), nil
+			}
+		}
+		return "", fmt.Errorf("Could not find suggested core reservation for instance type: %s\n", it)
+	}
+
+	return fmt.Sprintf("%dm", interpolateCpuReservation(cpuReservationTable, cores)), nil
+}
+
+// interpolateCpuReservation linearly interpolates the reserved millicores between the two table
+// entries surrounding cores (e.g. 72 vCPUs falls between the 64- and 96-core entries), capped at the
+// table's smallest/largest tabulated value for core counts outside the table's range.
+func interpolateCpuReservation(table []cpuReservationEntry, cores int64) int64 {
+	if cores <= table[0].cores {
+		return table[0].millicore
+	}
+	last := table[len(table)-1]
+	if cores >= last.cores {
+		return last.millicore
+	}
+	for i := 1; i < len(table); i++ {
+		if cores <= table[i].cores {
+			lo, hi := table[i-1], table[i]
+			fraction := float64(cores-lo.cores) / float64(hi.cores-lo.cores)
+			return lo.millicore + int64(fraction*float64(hi.millicore-lo.millicore))
+		}
+	}
+	return last.millicore
 }
 
-func getInstanceTypeCores(it string, meta *ClusterMeta) (int64, error) {
-	instTypeInfos, err := getInstanceTypeInfo(it, meta)
+func getInstanceTypeCores(it string, resolver *InstanceTypeResolver) (int64, error) {
+	instTypeInfos, err := getInstanceTypeInfo(it, resolver)
 	if err != nil {
 		return 0, err
 	}
@@ -300,24 +770,29 @@ type memEntry struct {
 }
 
 // See: https://docs.microsoft.com/en-us/azure/aks/concepts-clusters-workloads
+// max is denominated in MiB (the 4/8/16/128 GiB band edges from the Azure model, converted), so it
+// lines up with getInstanceTypeMemMi's true-MiB capacity and formatMem's "Mi" label.
 var memPercentages = []memEntry{
-	{max: 4, fraction: 0.25},
-	{max: 8, fraction: 0.20},
-	{max: 16, fraction: 0.10},
-	{max: 128, fraction: 0.06},
-	{max: 65535, fraction: 0.02},
+	{max: 4 * 1024, fraction: 0.25},
+	{max: 8 * 1024, fraction: 0.20},
+	{max: 16 * 1024, fraction: 0.10},
+	{max: 128 * 1024, fraction: 0.06},
+	{max: 65535 * 1024, fraction: 0.02},
 }
 
-func getMemReservations(it string, meta *ClusterMeta) (string, error) {
-	instMem, err := getInstanceTypeMem(it, meta)
+// getMemReservations bands memory reservation as a fraction of total memory, which is continuous
+// across every instance size. Exact and Interpolate therefore behave identically here; mode is only
+// accepted for interface parity with the other reservation getters (Skip is handled by the caller).
+func getMemReservations(it string, resolver *InstanceTypeResolver, mode ReservationMode) (string, error) {
+	instMemMi, err := getInstanceTypeMemMi(it, resolver)
 	if err != nil {
 		return "", err
 	}
 	var lower, reserved float64 = 0.0, 0.0
 	for _, memEnt := range memPercentages {
 		k, v := memEnt.max, memEnt.fraction
-		if instMem <= k {
-			reserved += v * (instMem - lower)
+		if instMemMi <= k {
+			reserved += v * (instMemMi - lower)
 			break
 		} else {
 			reserved += v * (k - lower)
@@ -337,8 +812,18 @@ func formatMem(f float64) string {
 	return ff + "Mi"
 }
 
-func getInstanceTypeMem(it string, meta *ClusterMeta) (float64, error) {
-	instTypeInfo, err := getInstanceTypeInfo(it, meta)
+// getInstanceTypeMemMi returns the instance type's total memory capacity in MiB, suitable for
+// comparing directly against MiB-denominated kubeReserved/systemReserved/evictionHard values.
+func getInstanceTypeMemMi(it string, resolver *InstanceTypeResolver) (float64, error) {
+	instTypeInfo, err := getInstanceTypeInfo(it, resolver)
+	if err != nil {
+		return 0, err
+	}
+	return float64(*(*instTypeInfo).MemoryInfo.SizeInMiB), nil
+}
+
+func getInstanceTypeMem(it string, resolver *InstanceTypeResolver) (float64, error) {
+	instTypeInfo, err := getInstanceTypeInfo(it, resolver)
 	if err != nil {
 		return 0, err
 	}
@@ -348,8 +833,12 @@ func getInstanceTypeMem(it string, meta *ClusterMeta) (float64, error) {
 	return strconv.ParseFloat(memStr, 64)
 }
 
-func getEphemeralStorageReservations(it string, meta *ClusterMeta) (string, error) {
-	storageSize, err := getInstanceTypeStorage(it, meta)
+// getEphemeralStorageReservations bands ephemeral-storage reservation as a fraction of total
+// instance storage, clamped to [1Gi, 15Gi], which is continuous across every instance size. Exact
+// and Interpolate therefore behave identically here; mode is only accepted for interface parity
+// with the other reservation getters (Skip is handled by the caller).
+func getEphemeralStorageReservations(it string, resolver *InstanceTypeResolver, mode ReservationMode) (string, error) {
+	storageSize, err := getInstanceTypeStorage(it, resolver)
 	if err != nil {
 		return "", err
 	}
@@ -371,9 +860,9 @@ func formatStorageSize(f float64) (string, error) {
 	return strconv.FormatFloat(f64, 'f', -1, 64) + "Gi", nil
 }
 
-func getInstanceTypeStorage(it string, meta *ClusterMeta) (int64, error) {
+func getInstanceTypeStorage(it string, resolver *InstanceTypeResolver) (int64, error) {
 	defaultInstanceTypeStorage := int64(20) //GB
-	instTypeInfo, err := getInstanceTypeInfo(it, meta)
+	instTypeInfo, err := getInstanceTypeInfo(it, resolver)
 	if err != nil {
 		return 0, err
 	}
@@ -385,31 +874,124 @@ func getInstanceTypeStorage(it string, meta *ClusterMeta) (int64, error) {
 	return *storageSize, nil
 }
 
-func getInstanceTypeInfo(it string, meta *ClusterMeta) (*ec2.InstanceTypeInfo, error) {
-	descInstTypeOutput, err := getInstanceTypeOutput(it, meta)
+func getInstanceTypeInfo(it string, resolver *InstanceTypeResolver) (*ec2.InstanceTypeInfo, error) {
+	return resolver.Get(it)
+}
+
+// instanceTypeBatchSize is the maximum number of instance types DescribeInstanceTypes accepts per call.
+const instanceTypeBatchSize = 100
+
+type instanceTypeKey struct {
+	region       string
+	instanceType string
+}
+
+// InstanceTypeResolver resolves EC2 instance type metadata for a ClusterConfig, sharing a single AWS
+// session and memoizing results across every nodegroup so that defaulting a ClusterConfig with N
+// nodegroups issues a small, batched number of DescribeInstanceTypes calls rather than one per
+// nodegroup per resource type.
+type InstanceTypeResolver struct {
+	sess   *session.Session
+	region string
+	mu     sync.Mutex
+	cache  map[instanceTypeKey]*ec2.InstanceTypeInfo
+}
+
+// NewInstanceTypeResolver creates an InstanceTypeResolver backed by a single AWS session for the
+// given cluster, to be shared across all of its nodegroups.
+func NewInstanceTypeResolver(meta *ClusterMeta) (*InstanceTypeResolver, error) {
+	sess, err := getSession(meta)
 	if err != nil {
 		return nil, err
 	}
-	instTypeInfos := descInstTypeOutput.InstanceTypes
-	if len(instTypeInfos) == 0 {
-		return nil, errors.New("No info found for instance type: " + it)
+	return &InstanceTypeResolver{
+		sess:   sess,
+		region: getRegion(meta),
+		cache:  make(map[instanceTypeKey]*ec2.InstanceTypeInfo),
+	}, nil
+}
+
+// WithStaticInstanceTypes returns an InstanceTypeResolver pre-seeded with the given instance types,
+// so that callers (unit tests, in particular) never need to hit AWS.
+func WithStaticInstanceTypes(types map[string]*ec2.InstanceTypeInfo) *InstanceTypeResolver {
+	r := &InstanceTypeResolver{cache: make(map[instanceTypeKey]*ec2.InstanceTypeInfo)}
+	for it, info := range types {
+		r.cache[instanceTypeKey{region: r.region, instanceType: it}] = info
 	}
-	return instTypeInfos[0], nil
+	return r
 }
 
-func getInstanceTypeOutput(it string, meta *ClusterMeta) (*ec2.DescribeInstanceTypesOutput, error) {
-	sess, err := getSession(meta)
-	if err != nil {
-		return nil, err
+// Prefetch issues a single batched DescribeInstanceTypes call (paginated at instanceTypeBatchSize)
+// for every instance type in instanceTypes that isn't already cached.
+func (r *InstanceTypeResolver) Prefetch(instanceTypes []string) error {
+	r.mu.Lock()
+	seen := make(map[string]bool, len(instanceTypes))
+	var missing []string
+	for _, it := range instanceTypes {
+		if it == "" || seen[it] {
+			continue
+		}
+		seen[it] = true
+		if _, ok := r.cache[instanceTypeKey{region: r.region, instanceType: it}]; !ok {
+			missing = append(missing, it)
+		}
 	}
-	svc := ec2.New(sess)
-	descInstanceTypesInput := &ec2.DescribeInstanceTypesInput{}
-	descInstanceTypesInput.SetInstanceTypes([]*string{&it})
-	descInstanceTypesOutput, err := svc.DescribeInstanceTypes(descInstanceTypesInput)
-	if err != nil {
+	r.mu.Unlock()
+
+	if len(missing) == 0 {
+		return nil
+	}
+	if r.sess == nil {
+		return errors.New("InstanceTypeResolver has no AWS session to resolve instance types with")
+	}
+
+	svc := ec2.New(r.sess)
+	for start := 0; start < len(missing); start += instanceTypeBatchSize {
+		end := start + instanceTypeBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		typePtrs := make([]*string, end-start)
+		for i, it := range missing[start:end] {
+			typePtrs[i] = aws.String(it)
+		}
+		input := &ec2.DescribeInstanceTypesInput{InstanceTypes: typePtrs}
+		err := svc.DescribeInstanceTypesPages(input, func(out *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
+			r.mu.Lock()
+			for _, info := range out.InstanceTypes {
+				r.cache[instanceTypeKey{region: r.region, instanceType: *info.InstanceType}] = info
+			}
+			r.mu.Unlock()
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the cached instance type info for it, fetching and memoizing it first if necessary.
+func (r *InstanceTypeResolver) Get(it string) (*ec2.InstanceTypeInfo, error) {
+	key := instanceTypeKey{region: r.region, instanceType: it}
+
+	r.mu.Lock()
+	info, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	if err := r.Prefetch([]string{it}); err != nil {
 		return nil, err
 	}
-	return descInstanceTypesOutput, nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if info, ok := r.cache[key]; ok {
+		return info, nil
+	}
+	return nil, errors.New("No info found for instance type: " + it)
 }
 
 func getRegion(meta *ClusterMeta) string {
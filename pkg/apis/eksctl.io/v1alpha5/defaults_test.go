@@ -0,0 +1,215 @@
+package v1alpha5
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// staticInstanceType builds the minimal ec2.InstanceTypeInfo fields this package reads, for use
+// with WithStaticInstanceTypes so these tests never need to hit AWS.
+func staticInstanceType(vcpus, memMi int64) *ec2.InstanceTypeInfo {
+	return &ec2.InstanceTypeInfo{
+		VCpuInfo:   &ec2.VCpuInfo{DefaultVCpus: aws.Int64(vcpus)},
+		MemoryInfo: &ec2.MemoryInfo{SizeInMiB: aws.Int64(memMi)},
+	}
+}
+
+func TestInterpolateCpuReservation(t *testing.T) {
+	cases := []struct {
+		name  string
+		cores int64
+		want  int64
+	}{
+		{"exact match at a tabulated entry", 8, 180},
+		{"below the smallest tabulated value clamps to the first entry", 0, 60},
+		{"between two entries interpolates linearly", 3, 120},
+		{"above the largest tabulated value clamps to the last entry", 256, 1040},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := interpolateCpuReservation(cpuReservationTable, tc.cores); got != tc.want {
+				t.Errorf("interpolateCpuReservation(%d) = %d, want %d", tc.cores, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetCpuReservationsReservationModes(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"m5.2xlarge": staticInstanceType(8, 32768),
+		"m5.3xlarge": staticInstanceType(12, 49152),
+	})
+
+	t.Run("Exact succeeds for a tabulated core count", func(t *testing.T) {
+		got, err := getCpuReservations("m5.2xlarge", resolver, ReservationModeExact)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "180m" {
+			t.Errorf("got %q, want %q", got, "180m")
+		}
+	})
+
+	t.Run("Exact errors for a non-tabulated core count", func(t *testing.T) {
+		if _, err := getCpuReservations("m5.3xlarge", resolver, ReservationModeExact); err == nil {
+			t.Fatal("expected an error for a non-tabulated core count, got nil")
+		}
+	})
+
+	t.Run("Interpolate succeeds for a non-tabulated core count", func(t *testing.T) {
+		got, err := getCpuReservations("m5.3xlarge", resolver, ReservationModeInterpolate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// 12 cores interpolates between the 8-core (180m) and 16-core (260m) entries.
+		if got != "220m" {
+			t.Errorf("got %q, want %q", got, "220m")
+		}
+	})
+}
+
+func TestSetReservationDefaultForKeySkip(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"m5.2xlarge": staticInstanceType(8, 32768),
+	})
+	ng := &NodeGroup{
+		InstanceType: "m5.2xlarge",
+		KubeletExtraConfig: &InlineDocument{
+			"reservationMode": "Skip",
+		},
+	}
+
+	if err := SetKubeletExtraConfigDefaults(ng, resolver); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kec := *ng.KubeletExtraConfig
+	if _, ok := kec["reservationMode"]; ok {
+		t.Error("reservationMode must be stripped from KubeletExtraConfig, not serialized to kubelet")
+	}
+	if _, ok := kec["kubeReserved"]; ok {
+		t.Error("kubeReserved must be left unset when reservationMode is Skip")
+	}
+	if _, ok := kec["systemReserved"]; ok {
+		t.Error("systemReserved must be left unset when reservationMode is Skip")
+	}
+}
+
+func TestValidateNodeAllocatable(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"m5.2xlarge": staticInstanceType(8, 32768), // 8 vCPUs, 32GiB = 32768Mi
+	})
+
+	t.Run("reservations well within capacity succeed", func(t *testing.T) {
+		ng := &NodeGroup{
+			InstanceType: "m5.2xlarge",
+			KubeletExtraConfig: &InlineDocument{
+				"kubeReserved": map[string]interface{}{"cpu": "500m", "memory": "1024.0Mi"},
+			},
+		}
+		if err := validateNodeAllocatable(ng, resolver); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("cpu reservations at or above capacity are rejected", func(t *testing.T) {
+		ng := &NodeGroup{
+			InstanceType: "m5.2xlarge",
+			KubeletExtraConfig: &InlineDocument{
+				"kubeReserved": map[string]interface{}{"cpu": "8000m"},
+			},
+		}
+		err := validateNodeAllocatable(ng, resolver)
+		if err == nil || !strings.Contains(err.Error(), "cpu") {
+			t.Fatalf("expected a cpu over-commit error, got %v", err)
+		}
+	})
+
+	t.Run("memory reservations at or above capacity are rejected", func(t *testing.T) {
+		// Regression test: kubeReserved.memory and the instance's MiB capacity must be compared in
+		// the same unit, or this case silently passes instead of erroring.
+		ng := &NodeGroup{
+			InstanceType: "m5.2xlarge",
+			KubeletExtraConfig: &InlineDocument{
+				"kubeReserved": map[string]interface{}{"memory": "32768.0Mi"},
+			},
+		}
+		err := validateNodeAllocatable(ng, resolver)
+		if err == nil || !strings.Contains(err.Error(), "memory") {
+			t.Fatalf("expected a memory over-commit error, got %v", err)
+		}
+	})
+}
+
+func TestSetMemoryManagerDefaultsNumaSplit(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"r5.24xlarge": staticInstanceType(96, 786432), // numaNodesByInstanceSuffix["24xlarge"] == 2
+	})
+	ng := &NodeGroup{
+		InstanceType: "r5.24xlarge",
+		KubeletExtraConfig: &InlineDocument{
+			"memoryManager":  map[string]interface{}{"policy": "Static"},
+			"kubeReserved":   map[string]interface{}{"memory": "2048.0Mi"},
+			"systemReserved": map[string]interface{}{"memory": "512.0Mi"},
+		},
+	}
+
+	if err := setMemoryManagerDefaults(ng, resolver); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kec := *ng.KubeletExtraConfig
+	if _, ok := kec["memoryManager"]; ok {
+		t.Error("memoryManager input key must be stripped, not serialized to kubelet")
+	}
+
+	reservedMemory, ok := kec["reservedMemory"].([]map[string]interface{})
+	if !ok || len(reservedMemory) != 2 {
+		t.Fatalf("expected 2 reservedMemory entries, got %#v", kec["reservedMemory"])
+	}
+	for i, entry := range reservedMemory {
+		limits := entry["limits"].(map[string]interface{})
+		// (2048 + 512) / 2 numa nodes = 1280Mi per node, in true MiB.
+		if got := limits["memory"]; got != "1280.0Mi" {
+			t.Errorf("reservedMemory[%d].limits.memory = %v, want 1280.0Mi", i, got)
+		}
+	}
+}
+
+func TestSetMemoryManagerDefaultsIncompatibleWithSkip(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"r5.24xlarge": staticInstanceType(96, 786432),
+	})
+	ng := &NodeGroup{
+		InstanceType: "r5.24xlarge",
+		KubeletExtraConfig: &InlineDocument{
+			"memoryManager": map[string]interface{}{"policy": "Static"},
+			// No kubeReserved/systemReserved populated, mirroring what reservationMode: Skip leaves.
+		},
+	}
+
+	if err := setMemoryManagerDefaults(ng, resolver); err == nil {
+		t.Fatal("expected an error when kubeReserved/systemReserved memory is unset, got nil")
+	}
+}
+
+func TestInstanceTypeResolverStaticCache(t *testing.T) {
+	resolver := WithStaticInstanceTypes(map[string]*ec2.InstanceTypeInfo{
+		"m5.2xlarge": staticInstanceType(8, 32768),
+	})
+
+	info, err := resolver.Get("m5.2xlarge")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *info.VCpuInfo.DefaultVCpus != 8 {
+		t.Errorf("DefaultVCpus = %d, want 8", *info.VCpuInfo.DefaultVCpus)
+	}
+
+	if _, err := resolver.Get("m5.unknown"); err == nil {
+		t.Fatal("expected an error resolving an uncached instance type with no AWS session, got nil")
+	}
+}